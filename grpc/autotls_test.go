@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// TestAutoTLSEndToEnd spins up a server and client purely in-memory: the
+// self-signed certificate and its CA bundle never touch disk, matching
+// the point of ServerAutoTLS/ClientTLSConfig.
+func TestAutoTLSEndToEnd(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer lis.Close()
+
+	const host = "127.0.0.1"
+	srv, err := NewGRPCServer(ServerAutoTLS(host), ServerHealth())
+	if err != nil {
+		t.Fatalf("NewGRPCServer: %v", err)
+	}
+	srv.Health.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	defer srv.Stop()
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(srv.CACert) {
+		t.Fatalf("failed to load CA bundle returned on Server.CACert")
+	}
+
+	conn, err := NewGRPCConn(lis.Addr().String(), ClientTLSConfig(&tls.Config{
+		ServerName: host,
+		RootCAs:    pool,
+	}))
+	if err != nil {
+		t.Fatalf("NewGRPCConn: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Fatalf("got status %v, want SERVING", resp.Status)
+	}
+}
@@ -0,0 +1,56 @@
+package certs
+
+import (
+	"crypto/x509"
+	"net"
+	"testing"
+)
+
+func TestGenerateSelfSigned(t *testing.T) {
+	cert, caPEM, err := GenerateSelfSigned("127.0.0.1", "localhost")
+	if err != nil {
+		t.Fatalf("GenerateSelfSigned: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		t.Fatalf("failed to parse returned CA bundle")
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("failed to parse generated certificate: %v", err)
+	}
+
+	if _, err := leaf.Verify(x509.VerifyOptions{DNSName: "localhost", Roots: pool}); err != nil {
+		t.Fatalf("certificate did not verify against its own CA bundle: %v", err)
+	}
+
+	if len(leaf.IPAddresses) != 1 || !leaf.IPAddresses[0].Equal(net.ParseIP("127.0.0.1")) {
+		t.Fatalf("expected 127.0.0.1 to be parsed as an IP SAN, got %v", leaf.IPAddresses)
+	}
+}
+
+func TestGenerateSelfSignedUniqueSerials(t *testing.T) {
+	certA, _, err := GenerateSelfSigned("localhost")
+	if err != nil {
+		t.Fatalf("GenerateSelfSigned: %v", err)
+	}
+	certB, _, err := GenerateSelfSigned("localhost")
+	if err != nil {
+		t.Fatalf("GenerateSelfSigned: %v", err)
+	}
+
+	leafA, err := x509.ParseCertificate(certA.Certificate[0])
+	if err != nil {
+		t.Fatalf("failed to parse certificate A: %v", err)
+	}
+	leafB, err := x509.ParseCertificate(certB.Certificate[0])
+	if err != nil {
+		t.Fatalf("failed to parse certificate B: %v", err)
+	}
+
+	if leafA.SerialNumber.Cmp(leafB.SerialNumber) == 0 {
+		t.Fatalf("expected distinct serial numbers across calls, got the same: %v", leafA.SerialNumber)
+	}
+}
@@ -9,6 +9,8 @@ import (
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -37,13 +39,47 @@ func ClientRetryMax(max uint) ClientOption {
 	}
 }
 
-// ClientCredentials set credentials
+// ClientCredentials set credentials. Since ClientServerConfig and
+// ClientServerConfigSystem took over verifying the server's certificate,
+// ClientCredentialsConfig is now only used to present a client
+// certificate for mutual TLS, or to dial without TLS via Insecure.
 func ClientCredentials(creds ClientCredentialsConfig) ClientOption {
 	return func(o *clientOptions) {
 		o.creds = creds
 	}
 }
 
+// ClientServerConfig sets the server name to verify and a CA file to
+// trust when verifying the server's certificate. Mutually exclusive
+// with ClientServerConfigSystem.
+func ClientServerConfig(serverName, caPath string) ClientOption {
+	return func(o *clientOptions) {
+		o.serverConfig.ServerName = serverName
+		o.serverConfig.CAPath = caPath
+	}
+}
+
+// ClientServerConfigSystem verifies the server's certificate against the
+// host's system trust store instead of a CA file. Mutually exclusive
+// with ClientServerConfig's caPath.
+func ClientServerConfigSystem(serverName string) ClientOption {
+	return func(o *clientOptions) {
+		o.serverConfig.ServerName = serverName
+		o.serverConfig.System = true
+	}
+}
+
+// ClientTLSConfig sets a ready-made *tls.Config on the client, bypassing
+// file-based credential loading entirely. Takes precedence over the
+// CertPath/KeyPath of ClientCredentials and over ClientServerConfig and
+// ClientServerConfigSystem; Insecure (see ClientCInsecure) still takes
+// precedence over it.
+func ClientTLSConfig(tlsConfig *tls.Config) ClientOption {
+	return func(o *clientOptions) {
+		o.tlsConfig = tlsConfig
+	}
+}
+
 // ClientCInsecure set insecure to true
 func ClientCInsecure() ClientOption {
 	return func(o *clientOptions) {
@@ -65,13 +101,31 @@ func StreamClientInterceptors(interceptors ...grpc.StreamClientInterceptor) Clie
 	}
 }
 
-// ClientCredentialsConfig is the config of client credentials
+// ClientPrometheus registers the default grpc_prometheus client metrics
+// with reg and installs its unary/stream interceptors. Use
+// WithHistograms() to additionally track per-call latency histograms.
+func ClientPrometheus(reg prometheus.Registerer, opts ...PrometheusOption) ClientOption {
+	return func(o *clientOptions) {
+		o.prometheusReg = reg
+		o.prometheusOpts = applyPrometheusOptions(opts...)
+	}
+}
+
+// ClientCredentialsConfig is the config of client credentials, used to
+// present a client certificate for mutual TLS, or, with Insecure, to
+// dial without TLS.
 type ClientCredentialsConfig struct {
-	CAPath     string
-	CertPath   string
-	KeyPath    string
+	CertPath string
+	KeyPath  string
+	Insecure bool
+}
+
+// clientServerConfig controls how the client verifies the server's
+// certificate: against a CA file, or against the system trust store.
+type clientServerConfig struct {
 	ServerName string
-	Insecure   bool
+	CAPath     string
+	System     bool
 }
 
 // NewGRPCConn return a grpc connection from the config
@@ -81,14 +135,27 @@ func NewGRPCConn(address string, opts ...ClientOption) (*grpc.ClientConn, error)
 	var options []grpc.DialOption
 	if o.creds.Insecure {
 		options = append(options, grpc.WithInsecure())
+	} else if o.tlsConfig != nil {
+		options = append(options, grpc.WithTransportCredentials(credentials.NewTLS(o.tlsConfig)))
 	} else {
+		if o.serverConfig.CAPath != "" && o.serverConfig.System {
+			return nil, fmt.Errorf("grpc: CAPath and the system cert pool cannot both be configured")
+		}
+
 		clientTLSConfig := &tls.Config{
-			ServerName: o.creds.ServerName,
+			ServerName: o.serverConfig.ServerName,
 		}
 
-		if o.creds.CAPath != "" {
+		switch {
+		case o.serverConfig.System:
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				return nil, fmt.Errorf("failed to load system cert pool: %w", err)
+			}
+			clientTLSConfig.RootCAs = pool
+		case o.serverConfig.CAPath != "":
 			cPool := x509.NewCertPool()
-			caCert, err := ioutil.ReadFile(o.creds.CAPath)
+			caCert, err := ioutil.ReadFile(o.serverConfig.CAPath)
 			if err != nil {
 				return nil, err
 			}
@@ -102,7 +169,7 @@ func NewGRPCConn(address string, opts ...ClientOption) (*grpc.ClientConn, error)
 		if o.creds.CertPath != "" && o.creds.KeyPath != "" {
 			clientCert, err := tls.LoadX509KeyPair(o.creds.CertPath, o.creds.KeyPath)
 			if err != nil {
-				return nil, fmt.Errorf("failed to append ca crt: %w", err)
+				return nil, fmt.Errorf("failed to load client certificate: %w", err)
 			}
 			clientTLSConfig.Certificates = []tls.Certificate{clientCert}
 		}
@@ -110,6 +177,16 @@ func NewGRPCConn(address string, opts ...ClientOption) (*grpc.ClientConn, error)
 		options = append(options, grpc.WithTransportCredentials(creds))
 	}
 
+	unaryInterceptors := o.unaryInterceptors
+	streamInterceptors := o.streamInterceptors
+	if o.prometheusReg != nil {
+		registerPrometheusClientMetrics(o.prometheusReg, o.prometheusOpts)
+		unaryInterceptors = append([]grpc.UnaryClientInterceptor{grpc_prometheus.UnaryClientInterceptor}, unaryInterceptors...)
+		streamInterceptors = append([]grpc.StreamClientInterceptor{grpc_prometheus.StreamClientInterceptor}, streamInterceptors...)
+	}
+	unaryInterceptors = append(unaryInterceptors, ErrorUnaryClientInterceptor())
+	streamInterceptors = append(streamInterceptors, ErrorStreamClientInterceptor())
+
 	retryOpts := []grpc_retry.CallOption{
 		grpc_retry.WithBackoff(grpc_retry.BackoffLinear(time.Second)),
 		grpc_retry.WithMax(o.retryMax),
@@ -118,8 +195,8 @@ func NewGRPCConn(address string, opts ...ClientOption) (*grpc.ClientConn, error)
 	options = append(options,
 		grpc.WithStreamInterceptor(grpc_retry.StreamClientInterceptor(retryOpts...)),
 		grpc.WithUnaryInterceptor(grpc_retry.UnaryClientInterceptor(retryOpts...)),
-		grpc.WithUnaryInterceptor(grpc_middleware.ChainUnaryClient(o.unaryInterceptors...)),
-		grpc.WithStreamInterceptor(grpc_middleware.ChainStreamClient(o.streamInterceptors...)),
+		grpc.WithUnaryInterceptor(grpc_middleware.ChainUnaryClient(unaryInterceptors...)),
+		grpc.WithStreamInterceptor(grpc_middleware.ChainStreamClient(streamInterceptors...)),
 	)
 
 	return grpc.Dial(address, options...)
@@ -130,6 +207,10 @@ type clientOptions struct {
 	retryMax           uint
 	retryTimeout       time.Duration
 	creds              ClientCredentialsConfig
+	serverConfig       clientServerConfig
+	tlsConfig          *tls.Config
+	prometheusReg      prometheus.Registerer
+	prometheusOpts     *prometheusOptions
 	streamInterceptors []grpc.StreamClientInterceptor
 	unaryInterceptors  []grpc.UnaryClientInterceptor
 }
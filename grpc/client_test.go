@@ -0,0 +1,94 @@
+package grpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewGRPCConnCAPathAndSystemMutuallyExclusive(t *testing.T) {
+	_, err := NewGRPCConn("127.0.0.1:0",
+		ClientServerConfig("example.com", "/tmp/does-not-matter-ca.pem"),
+		ClientServerConfigSystem("example.com"),
+	)
+	if err == nil {
+		t.Fatalf("expected an error when both CAPath and the system cert pool are configured")
+	}
+	if !strings.Contains(err.Error(), "CAPath and the system cert pool cannot both be configured") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// TestNewGRPCConnSystemRootsWithClientCert proves ClientServerConfigSystem
+// composes with ClientCredentials: verifying the server against the
+// system trust store doesn't prevent also presenting a client
+// certificate for mutual TLS. grpc.Dial doesn't connect eagerly, so a
+// successful return here means NewGRPCConn accepted and wired up both
+// options together rather than rejecting the combination.
+func TestNewGRPCConnSystemRootsWithClientCert(t *testing.T) {
+	certPath, keyPath := writeSelfSignedKeyPair(t)
+
+	conn, err := NewGRPCConn("127.0.0.1:0",
+		ClientServerConfigSystem("example.com"),
+		ClientCredentials(ClientCredentialsConfig{CertPath: certPath, KeyPath: keyPath}),
+	)
+	if err != nil {
+		t.Fatalf("NewGRPCConn: %v", err)
+	}
+	defer conn.Close()
+}
+
+// writeSelfSignedKeyPair writes a throwaway self-signed cert/key pair
+// to files under t.TempDir, for exercising the CertPath/KeyPath
+// (tls.LoadX509KeyPair) code path in NewGRPCConn.
+func writeSelfSignedKeyPair(t *testing.T) (certPath, keyPath string) {
+	t.Helper()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"goxb test"}},
+		NotBefore:             now,
+		NotAfter:              now.Add(time.Hour),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		t.Fatalf("failed to marshal private key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certPath = filepath.Join(dir, "cert.pem")
+	keyPath = filepath.Join(dir, "key.pem")
+
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write cert: %v", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	return certPath, keyPath
+}
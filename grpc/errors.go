@@ -0,0 +1,207 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorMapping is a sentinel error, matched via errors.Is, mapped to the
+// gRPC code it should be reported as.
+type errorMapping struct {
+	sentinel error
+	code     codes.Code
+}
+
+var (
+	errorMappingsMu sync.RWMutex
+	errorMappings   = []errorMapping{
+		{context.Canceled, codes.Canceled},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+	}
+)
+
+// RegisterErrorMapping registers sentinel (matched via errors.Is) so
+// that ErrorUnaryServerInterceptor and ErrorStreamServerInterceptor
+// report it to the client as code instead of the default
+// codes.Unknown, and so ErrorUnaryClientInterceptor/
+// ErrorStreamClientInterceptor can rehydrate it back into sentinel on
+// the calling side. When several sentinels share a code, the client
+// rehydrates the one registered first, so the Canceled and
+// DeadlineExceeded defaults are always the ones rehydrated for those
+// codes.
+//
+// RegisterErrorMapping is safe to call concurrently, but like any other
+// global registration it should be done during init, before the server
+// or client starts handling calls - registering a mapping mid-traffic
+// means in-flight calls may see either the old or the new mapping set.
+func RegisterErrorMapping(sentinel error, code codes.Code) {
+	errorMappingsMu.Lock()
+	defer errorMappingsMu.Unlock()
+	errorMappings = append(errorMappings, errorMapping{sentinel, code})
+}
+
+func codeForError(err error) codes.Code {
+	errorMappingsMu.RLock()
+	defer errorMappingsMu.RUnlock()
+	for _, m := range errorMappings {
+		if errors.Is(err, m.sentinel) {
+			return m.code
+		}
+	}
+
+	return codes.Unknown
+}
+
+func sentinelForCode(code codes.Code) error {
+	errorMappingsMu.RLock()
+	defer errorMappingsMu.RUnlock()
+	for _, m := range errorMappings {
+		if m.code == code {
+			return m.sentinel
+		}
+	}
+
+	return nil
+}
+
+// toStatusError converts a handler error into one backed by a
+// *status.Status so it round-trips over the wire. Errors that already
+// carry a gRPC status (e.g. produced via status.Error) are passed
+// through unchanged, as is io.EOF, which streaming handlers use as an
+// end-of-stream sentinel rather than a real error. Everything else is
+// mapped via codeForError, with the original error text attached as a
+// google.rpc.DebugInfo detail so ErrorUnaryClientInterceptor/
+// ErrorStreamClientInterceptor can recover it.
+func toStatusError(err error) error {
+	if err == nil || errors.Is(err, io.EOF) {
+		return err
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	st := status.New(codeForError(err), err.Error())
+	if withDetails, detailErr := st.WithDetails(&errdetails.DebugInfo{Detail: err.Error()}); detailErr == nil {
+		st = withDetails
+	}
+
+	return st.Err()
+}
+
+// sentinelStatusError wraps a sentinel error with the *status.Status it
+// was rehydrated from. It still implements GRPCStatus(), so interceptors
+// running outside ErrorUnaryClientInterceptor/ErrorStreamClientInterceptor
+// in the chain (e.g. the chunk0-3 grpc_prometheus interceptors) keep
+// seeing the real status code via status.FromError, while errors.Is
+// against the sentinel keeps working too.
+type sentinelStatusError struct {
+	status   *status.Status
+	sentinel error
+	msg      string
+}
+
+func (e *sentinelStatusError) Error() string {
+	return fmt.Sprintf("%s: %s", e.msg, e.sentinel)
+}
+
+func (e *sentinelStatusError) Unwrap() error {
+	return e.sentinel
+}
+
+func (e *sentinelStatusError) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// fromStatusError inverts toStatusError on the client: it pulls the
+// DebugInfo detail back out of the status, if present, and for codes
+// registered via RegisterErrorMapping (or the Canceled/DeadlineExceeded
+// defaults) wraps the status error with the matching sentinel, so
+// callers can keep using errors.Is(err, context.Canceled) transparently,
+// while anything inspecting the status code (status.FromError,
+// status.Code) still sees the original one.
+func fromStatusError(err error) error {
+	if err == nil || errors.Is(err, io.EOF) {
+		return err
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	sentinel := sentinelForCode(st.Code())
+	if sentinel == nil {
+		return err
+	}
+
+	msg := st.Message()
+	for _, d := range st.Details() {
+		if di, ok := d.(*errdetails.DebugInfo); ok {
+			msg = di.Detail
+		}
+	}
+
+	return &sentinelStatusError{status: st, sentinel: sentinel, msg: msg}
+}
+
+// ErrorUnaryServerInterceptor converts errors returned by unary handlers
+// into status errors that round-trip the original error chain to the
+// client; see toStatusError.
+func ErrorUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, toStatusError(err)
+	}
+}
+
+// ErrorStreamServerInterceptor is the streaming equivalent of
+// ErrorUnaryServerInterceptor.
+func ErrorStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return toStatusError(handler(srv, stream))
+	}
+}
+
+// ErrorUnaryClientInterceptor inverts ErrorUnaryServerInterceptor: it
+// rehydrates well-known sentinel errors from the returned status so
+// callers can keep using errors.Is; see fromStatusError.
+func ErrorUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return fromStatusError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// ErrorStreamClientInterceptor is the streaming equivalent of
+// ErrorUnaryClientInterceptor. It wraps the returned ClientStream so
+// errors surfaced from SendMsg/RecvMsg are translated too.
+func ErrorStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, fromStatusError(err)
+		}
+
+		return &errorClientStream{ClientStream: stream}, nil
+	}
+}
+
+type errorClientStream struct {
+	grpc.ClientStream
+}
+
+func (s *errorClientStream) SendMsg(m interface{}) error {
+	return fromStatusError(s.ClientStream.SendMsg(m))
+}
+
+func (s *errorClientStream) RecvMsg(m interface{}) error {
+	return fromStatusError(s.ClientStream.RecvMsg(m))
+}
@@ -0,0 +1,123 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatusFromStatusErrorRoundTrip(t *testing.T) {
+	cases := []struct {
+		name     string
+		sentinel error
+		code     codes.Code
+	}{
+		{"canceled", context.Canceled, codes.Canceled},
+		{"deadline exceeded", context.DeadlineExceeded, codes.DeadlineExceeded},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := errors.New("operation failed: " + tc.sentinel.Error())
+			wrapped := &sentinelWrapError{msg: original.Error(), sentinel: tc.sentinel}
+
+			statusErr := toStatusError(wrapped)
+			if statusErr == nil {
+				t.Fatalf("toStatusError returned nil")
+			}
+			if got := status.Code(statusErr); got != tc.code {
+				t.Fatalf("toStatusError code = %s, want %s", got, tc.code)
+			}
+
+			got := fromStatusError(statusErr)
+			if !errors.Is(got, tc.sentinel) {
+				t.Fatalf("fromStatusError(%v) = %v, does not match sentinel %v via errors.Is", statusErr, got, tc.sentinel)
+			}
+			if got := status.Code(got); got != tc.code {
+				t.Fatalf("fromStatusError(%v) code = %s, want %s", statusErr, got, tc.code)
+			}
+		})
+	}
+}
+
+func TestToStatusErrorUnmappedDefaultsToUnknown(t *testing.T) {
+	err := errors.New("some unmapped failure")
+	statusErr := toStatusError(err)
+	if got := status.Code(statusErr); got != codes.Unknown {
+		t.Fatalf("toStatusError code = %s, want %s", got, codes.Unknown)
+	}
+
+	st, _ := status.FromError(statusErr)
+	var gotDetail string
+	for _, d := range st.Details() {
+		if di, ok := d.(*errdetails.DebugInfo); ok {
+			gotDetail = di.Detail
+		}
+	}
+	if gotDetail != err.Error() {
+		t.Fatalf("DebugInfo detail = %q, want %q", gotDetail, err.Error())
+	}
+}
+
+func TestToStatusErrorPassesThroughEOF(t *testing.T) {
+	if got := toStatusError(io.EOF); got != io.EOF {
+		t.Fatalf("toStatusError(io.EOF) = %v, want io.EOF unchanged", got)
+	}
+	if got := fromStatusError(io.EOF); got != io.EOF {
+		t.Fatalf("fromStatusError(io.EOF) = %v, want io.EOF unchanged", got)
+	}
+}
+
+func TestToStatusErrorPassesThroughNil(t *testing.T) {
+	if got := toStatusError(nil); got != nil {
+		t.Fatalf("toStatusError(nil) = %v, want nil", got)
+	}
+}
+
+func TestToStatusErrorPassesThroughExistingStatus(t *testing.T) {
+	original := status.Error(codes.NotFound, "not found")
+	got := toStatusError(original)
+	if got != original {
+		t.Fatalf("toStatusError did not pass an already-status-backed error through unchanged: got %v, want %v", got, original)
+	}
+}
+
+func TestFromStatusErrorUnmappedCodePassesThrough(t *testing.T) {
+	original := status.Error(codes.NotFound, "not found")
+	got := fromStatusError(original)
+	if got != original {
+		t.Fatalf("fromStatusError should pass through codes with no registered sentinel unchanged: got %v, want %v", got, original)
+	}
+}
+
+func TestRegisterErrorMappingExtensibility(t *testing.T) {
+	sentinel := errors.New("custom sentinel")
+	RegisterErrorMapping(sentinel, codes.AlreadyExists)
+
+	wrapped := &sentinelWrapError{msg: "create failed: " + sentinel.Error(), sentinel: sentinel}
+	statusErr := toStatusError(wrapped)
+	if got := status.Code(statusErr); got != codes.AlreadyExists {
+		t.Fatalf("toStatusError code = %s, want %s", got, codes.AlreadyExists)
+	}
+
+	got := fromStatusError(statusErr)
+	if !errors.Is(got, sentinel) {
+		t.Fatalf("fromStatusError(%v) does not match registered sentinel via errors.Is", statusErr)
+	}
+}
+
+// sentinelWrapError lets tests build an error whose errors.Is chain
+// reaches a given sentinel, the way a real handler error built with
+// fmt.Errorf("...: %w", sentinel) would.
+type sentinelWrapError struct {
+	msg      string
+	sentinel error
+}
+
+func (e *sentinelWrapError) Error() string { return e.msg }
+func (e *sentinelWrapError) Unwrap() error { return e.sentinel }
@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"net/http"
+
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+)
+
+// PrometheusOption configures the grpc_prometheus metrics interceptors
+// installed by ServerPrometheus and ClientPrometheus.
+type PrometheusOption func(*prometheusOptions)
+
+// WithHistograms enables per-call latency histograms, which
+// grpc_prometheus leaves disabled by default because of their
+// cardinality cost.
+func WithHistograms() PrometheusOption {
+	return func(o *prometheusOptions) {
+		o.histograms = true
+	}
+}
+
+type prometheusOptions struct {
+	histograms bool
+}
+
+func applyPrometheusOptions(opts ...PrometheusOption) *prometheusOptions {
+	o := &prometheusOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// MetricsHandler returns an http.Handler serving Prometheus metrics, for
+// callers to mount at e.g. /metrics on their own mux.
+func MetricsHandler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterServerMetrics initializes the per-method label values for all
+// services registered on grpcServer, so they show up with a zero count
+// before the first call rather than only appearing once invoked.
+func RegisterServerMetrics(grpcServer *grpc.Server) {
+	grpc_prometheus.Register(grpcServer)
+}
+
+func registerPrometheusServerMetrics(reg prometheus.Registerer, opts *prometheusOptions) {
+	if opts.histograms {
+		grpc_prometheus.EnableHandlingTimeHistogram()
+	}
+	reg.MustRegister(grpc_prometheus.DefaultServerMetrics)
+}
+
+func registerPrometheusClientMetrics(reg prometheus.Registerer, opts *prometheusOptions) {
+	if opts.histograms {
+		grpc_prometheus.EnableClientHandlingTimeHistogram()
+	}
+	reg.MustRegister(grpc_prometheus.DefaultClientMetrics)
+}
@@ -8,9 +8,16 @@ import (
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/goxb/pkg/grpc/certs"
 )
 
 type ServerOption func(*serverOptions)
@@ -36,6 +43,25 @@ func ServerCredentials(creds ServerCredentialsConfig) ServerOption {
 	}
 }
 
+// ServerTLSConfig sets a ready-made *tls.Config on the server, bypassing
+// file-based credential loading entirely. Takes precedence over
+// ServerCredentials and ServerAutoTLS.
+func ServerTLSConfig(tlsConfig *tls.Config) ServerOption {
+	return func(o *serverOptions) {
+		o.tlsConfig = tlsConfig
+	}
+}
+
+// ServerAutoTLS generates an in-memory self-signed certificate for the
+// given hosts via certs.GenerateSelfSigned, sparing callers from
+// managing TLS material on disk. Useful for tests and ephemeral
+// environments.
+func ServerAutoTLS(hosts ...string) ServerOption {
+	return func(o *serverOptions) {
+		o.autoTLSHosts = hosts
+	}
+}
+
 // UnaryServerInterceptors set unary server interceptors
 func UnaryServerInterceptors(interceptors ...grpc.UnaryServerInterceptor) ServerOption {
 	return func(o *serverOptions) {
@@ -50,9 +76,50 @@ func StreamServerInterceptors(interceptors ...grpc.StreamServerInterceptor) Serv
 	}
 }
 
+// ServerPrometheus registers the default grpc_prometheus server metrics
+// with reg and installs its unary/stream interceptors ahead of the
+// recovery interceptor. Use WithHistograms() to additionally track
+// per-call latency histograms.
+func ServerPrometheus(reg prometheus.Registerer, opts ...PrometheusOption) ServerOption {
+	return func(o *serverOptions) {
+		o.prometheusReg = reg
+		o.prometheusOpts = applyPrometheusOptions(opts...)
+	}
+}
+
+// ServerReflection registers the gRPC reflection service, so tools like
+// grpcurl can discover services without a local copy of the proto.
+func ServerReflection() ServerOption {
+	return func(o *serverOptions) {
+		o.postInit = append(o.postInit, func(s *grpc.Server) {
+			reflection.Register(s)
+		})
+	}
+}
+
+// ServerHealth registers the standard grpc_health_v1 health service with
+// a default health.Server. The server is reachable on the struct
+// returned by NewGRPCServer, so callers can drive it from readiness
+// probes via Health.SetServingStatus.
+func ServerHealth() ServerOption {
+	hs := health.NewServer()
+	return func(o *serverOptions) {
+		o.health = hs
+		o.postInit = append(o.postInit, func(s *grpc.Server) {
+			grpc_health_v1.RegisterHealthServer(s, hs)
+		})
+	}
+}
+
 type serverOptions struct {
 	logger             *zap.Logger
 	creds              ServerCredentialsConfig
+	tlsConfig          *tls.Config
+	autoTLSHosts       []string
+	prometheusReg      prometheus.Registerer
+	prometheusOpts     *prometheusOptions
+	health             *health.Server
+	postInit           []func(*grpc.Server)
 	streamInterceptors []grpc.StreamServerInterceptor
 	unaryInterceptors  []grpc.UnaryServerInterceptor
 }
@@ -66,15 +133,40 @@ func applyServerOptions(opts ...ServerOption) *serverOptions {
 	return o
 }
 
+// Server wraps a *grpc.Server. Health is non-nil when the server was
+// built with ServerHealth, letting callers drive readiness probes via
+// Health.SetServingStatus. CACert is non-nil when the server was built
+// with ServerAutoTLS: it holds the PEM-encoded CA bundle for the
+// generated self-signed certificate, for configuring a client's trust
+// pool via ClientTLSConfig or ClientServerConfig.
+type Server struct {
+	*grpc.Server
+	Health *health.Server
+	CACert []byte
+}
+
 // NewGRPCServer create grpc server
-func NewGRPCServer(options ...ServerOption) (*grpc.Server, error) {
+func NewGRPCServer(options ...ServerOption) (*Server, error) {
 	o := applyServerOptions(options...)
 
 	opts := []grpc.ServerOption{}
+	var caCert []byte
 	creds := o.creds
-	if creds.CAPath == "" && creds.CertPath == "" && creds.KeyPath == "" {
+	switch {
+	case o.tlsConfig != nil:
+		o.logger.Info("enable TLS in grpc server from supplied tls.Config")
+		opts = append(opts, grpc.Creds(credentials.NewTLS(o.tlsConfig)))
+	case len(o.autoTLSHosts) > 0:
+		o.logger.Info("enable TLS in grpc server with auto-generated self-signed certificate")
+		cert, ca, err := certs.GenerateSelfSigned(o.autoTLSHosts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate self-signed certificate: %w", err)
+		}
+		caCert = ca
+		opts = append(opts, grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}})))
+	case creds.CAPath == "" && creds.CertPath == "" && creds.KeyPath == "":
 		o.logger.Info("No TLS keys, insecure mode")
-	} else {
+	default:
 		// TLS authentication, otherwise run without authentication.
 		if creds.CAPath == "" {
 			o.logger.Info("enable sample credentials in grpc server")
@@ -120,14 +212,20 @@ func NewGRPCServer(options ...ServerOption) (*grpc.Server, error) {
 	}
 	rec := grpc_recovery.WithRecoveryHandler(recoveryFn)
 
-	unaryInterceptors := []grpc.UnaryServerInterceptor{
-		grpc_recovery.UnaryServerInterceptor(rec),
+	var unaryInterceptors []grpc.UnaryServerInterceptor
+	var streamInterceptors []grpc.StreamServerInterceptor
+	if o.prometheusReg != nil {
+		registerPrometheusServerMetrics(o.prometheusReg, o.prometheusOpts)
+		unaryInterceptors = append(unaryInterceptors, grpc_prometheus.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, grpc_prometheus.StreamServerInterceptor)
 	}
+
+	unaryInterceptors = append(unaryInterceptors, grpc_recovery.UnaryServerInterceptor(rec))
 	unaryInterceptors = append(unaryInterceptors, o.unaryInterceptors...)
-	streamInterceptors := []grpc.StreamServerInterceptor{
-		grpc_recovery.StreamServerInterceptor(rec),
-	}
+	unaryInterceptors = append(unaryInterceptors, ErrorUnaryServerInterceptor())
+	streamInterceptors = append(streamInterceptors, grpc_recovery.StreamServerInterceptor(rec))
 	streamInterceptors = append(streamInterceptors, o.streamInterceptors...)
+	streamInterceptors = append(streamInterceptors, ErrorStreamServerInterceptor())
 
 	opts = append(opts,
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(unaryInterceptors...)),
@@ -135,5 +233,9 @@ func NewGRPCServer(options ...ServerOption) (*grpc.Server, error) {
 	)
 
 	grpcServer := grpc.NewServer(opts...)
-	return grpcServer, nil
+	for _, hook := range o.postInit {
+		hook(grpcServer)
+	}
+
+	return &Server{Server: grpcServer, Health: o.health, CACert: caCert}, nil
 }
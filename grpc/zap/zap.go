@@ -2,13 +2,15 @@ package grpc
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 type LoggerFeild func(context.Context) zap.Field
@@ -22,56 +24,178 @@ func applyLoggerFeilds(ctx context.Context, lfs ...LoggerFeild) []zap.Field {
 	return fields
 }
 
-// UnaryServerInterceptor returns a new unary server interceptors that adds zap.Logger to the context.
-func UnaryServerInterceptor(logger *zap.Logger, opts ...LoggerFeild) grpc.UnaryServerInterceptor {
+// CodeToLevel maps a gRPC status code to the zap level a call finishing
+// with that code should be logged at.
+type CodeToLevel func(code codes.Code) zapcore.Level
+
+// DurationToField renders a call's duration as a zap.Field.
+type DurationToField func(duration time.Duration) zap.Field
+
+// PayloadDecider decides, per call, whether its request/response
+// payloads should additionally be logged at Debug.
+type PayloadDecider func(ctx context.Context, fullMethod string) bool
+
+// Option configures NewUnaryServerInterceptor/NewStreamServerInterceptor.
+type Option func(*options)
+
+// WithFields attaches extra zap fields derived from the request context,
+// e.g. a request ID pulled out of metadata.
+func WithFields(lfs ...LoggerFeild) Option {
+	return func(o *options) {
+		o.fields = append(o.fields, lfs...)
+	}
+}
+
+// WithCodeToLevel overrides the default gRPC code -> zap level mapping.
+func WithCodeToLevel(f CodeToLevel) Option {
+	return func(o *options) {
+		o.codeToLevel = f
+	}
+}
+
+// WithDurationField overrides how the call duration is rendered; by
+// default it is logged as a formatted string under grpc.duration.
+func WithDurationField(f DurationToField) Option {
+	return func(o *options) {
+		o.durationToField = f
+	}
+}
+
+// WithPayloadLogging enables marshaling the request and response via
+// protojson and logging them at Debug whenever decider returns true for
+// the call's full method. Only NewUnaryServerInterceptor logs payloads;
+// NewStreamServerInterceptor ignores this option.
+func WithPayloadLogging(decider PayloadDecider) Option {
+	return func(o *options) {
+		o.payloadDecider = decider
+	}
+}
+
+type options struct {
+	fields          []LoggerFeild
+	codeToLevel     CodeToLevel
+	durationToField DurationToField
+	payloadDecider  PayloadDecider
+}
+
+func applyOptions(opts ...Option) *options {
+	o := &options{
+		codeToLevel:     DefaultCodeToLevel,
+		durationToField: defaultDurationToField,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// DefaultCodeToLevel is the default gRPC code -> zap level mapping,
+// mirroring grpc-ecosystem/go-grpc-middleware's logging/zap package:
+// codes a client is expected to hit in normal operation log at Info,
+// codes that indicate a misbehaving client or caller log at Warn, and
+// everything else - unexpected server-side failure - logs at Error.
+func DefaultCodeToLevel(code codes.Code) zapcore.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.Unauthenticated:
+		return zapcore.InfoLevel
+	case codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted, codes.FailedPrecondition, codes.Aborted, codes.OutOfRange, codes.Unavailable:
+		return zapcore.WarnLevel
+	default:
+		// codes.Unknown, codes.Unimplemented, codes.Internal, codes.DataLoss, and anything unrecognized.
+		return zapcore.ErrorLevel
+	}
+}
+
+func defaultDurationToField(duration time.Duration) zap.Field {
+	return zap.String("grpc.duration", duration.String())
+}
+
+func logPayload(logger *zap.Logger, fullMethod, field string, msg interface{}) {
+	pm, ok := msg.(proto.Message)
+	if !ok {
+		return
+	}
+
+	payload, err := protojson.Marshal(pm)
+	if err != nil {
+		logger.Debug("failed to marshal grpc payload for logging", zap.String("grpc.method", fullMethod), zap.Error(err))
+		return
+	}
+
+	logger.Debug("grpc payload", zap.String("grpc.method", fullMethod), zap.String(field, string(payload)))
+}
+
+func logCall(logger *zap.Logger, o *options, ctx context.Context, msg, fullMethod string, duration time.Duration, err error) {
+	code := status.Code(err)
+	fields := applyLoggerFeilds(ctx, o.fields...)
+	fields = append(fields,
+		zap.String("grpc.method", fullMethod),
+		zap.String("grpc.code", code.String()),
+		o.durationToField(duration),
+	)
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+
+	logger.With(fields...).Check(o.codeToLevel(code), msg).Write()
+}
+
+// UnaryServerInterceptor returns a new unary server interceptor that logs
+// each call as structured zap fields, at a level driven by the returned
+// status code. fields are the same LoggerFeild values this function
+// accepted before Option existed; use NewUnaryServerInterceptor for the
+// WithCodeToLevel/WithDurationField/WithPayloadLogging options.
+func UnaryServerInterceptor(logger *zap.Logger, fields ...LoggerFeild) grpc.UnaryServerInterceptor {
+	return NewUnaryServerInterceptor(logger, WithFields(fields...))
+}
+
+// NewUnaryServerInterceptor is the Option-configurable counterpart to
+// UnaryServerInterceptor.
+func NewUnaryServerInterceptor(logger *zap.Logger, opts ...Option) grpc.UnaryServerInterceptor {
+	o := applyOptions(opts...)
+
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		startTime := time.Now()
 		resp, err := handler(ctx, req)
-		endTime := time.Now()
-
-		code := status.Code(err)
-		fields := applyLoggerFeilds(ctx, opts...)
+		duration := time.Since(startTime)
 
-		var msg string
-		if err != nil {
-			msg = fmt.Sprintf("latency=%-12s %s %s, err=%s", endTime.Sub(startTime).String(), info.FullMethod, code.String(), err)
-		} else {
-			msg = fmt.Sprintf("latency=%-12s %s %s", endTime.Sub(startTime).String(), info.FullMethod, code.String())
-		}
+		logCall(logger, o, ctx, "finished unary call", info.FullMethod, duration, err)
 
-		if code == codes.Internal || code == codes.Unavailable {
-			logger.With(fields...).Error(msg)
-		} else {
-			logger.With(fields...).Info(msg)
+		if o.payloadDecider != nil && o.payloadDecider(ctx, info.FullMethod) {
+			logPayload(logger, info.FullMethod, "grpc.request", req)
+			if err == nil {
+				logPayload(logger, info.FullMethod, "grpc.response", resp)
+			}
 		}
 
 		return resp, err
 	}
 }
 
-// StreamServerInterceptor returns a new streaming server interceptor that adds zap.Logger to the context.
-func StreamServerInterceptor(logger *zap.Logger, opts ...LoggerFeild) grpc.StreamServerInterceptor {
+// StreamServerInterceptor returns a new streaming server interceptor that
+// logs each call as structured zap fields, at a level driven by the
+// returned status code. fields are the same LoggerFeild values this
+// function accepted before Option existed; use
+// NewStreamServerInterceptor for the WithCodeToLevel/WithDurationField
+// options.
+func StreamServerInterceptor(logger *zap.Logger, fields ...LoggerFeild) grpc.StreamServerInterceptor {
+	return NewStreamServerInterceptor(logger, WithFields(fields...))
+}
+
+// NewStreamServerInterceptor is the Option-configurable counterpart to
+// StreamServerInterceptor.
+func NewStreamServerInterceptor(logger *zap.Logger, opts ...Option) grpc.StreamServerInterceptor {
+	o := applyOptions(opts...)
+
 	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		ctx := stream.Context()
 
 		startTime := time.Now()
 		err := handler(srv, stream)
-		endTime := time.Now()
-
-		code := status.Code(err)
-		fields := applyLoggerFeilds(ctx, opts...)
-		var msg string
-		if err != nil {
-			msg = fmt.Sprintf("latency=%-12s %s %s, err=%s", endTime.Sub(startTime).String(), info.FullMethod, code.String(), err)
-		} else {
-			msg = fmt.Sprintf("latency=%-12s %s %s", endTime.Sub(startTime).String(), info.FullMethod, code.String())
-		}
+		duration := time.Since(startTime)
 
-		if code == codes.Internal || code == codes.Unavailable {
-			logger.With(fields...).Error(msg)
-		} else {
-			logger.With(fields...).Info(msg)
-		}
+		logCall(logger, o, ctx, "finished streaming call", info.FullMethod, duration, err)
 
 		return err
 	}
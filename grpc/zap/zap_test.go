@@ -0,0 +1,152 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestUnaryServerInterceptorAcceptsBareClosures proves the pre-Option
+// call signature still compiles with an inline closure, the way every
+// caller of the original ...LoggerFeild signature would write it -
+// without an explicit LoggerFeild(...) conversion.
+func TestUnaryServerInterceptorAcceptsBareClosures(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(core)
+
+	interceptor := UnaryServerInterceptor(logger, func(ctx context.Context) zap.Field {
+		return zap.String("request.id", "abc")
+	})
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	if _, err := interceptor(context.Background(), nil, &grpclib.UnaryServerInfo{FullMethod: "/svc/Method"}, handler); err != nil {
+		t.Fatalf("interceptor: %v", err)
+	}
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry, got %d", len(entries))
+	}
+	if v, ok := entries[0].ContextMap()["request.id"]; !ok || v != "abc" {
+		t.Fatalf("expected request.id=abc field, got %v", entries[0].ContextMap())
+	}
+}
+
+// TestStreamServerInterceptorAcceptsBareClosures is the streaming
+// equivalent of TestUnaryServerInterceptorAcceptsBareClosures.
+func TestStreamServerInterceptorAcceptsBareClosures(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(core)
+
+	interceptor := StreamServerInterceptor(logger, func(ctx context.Context) zap.Field {
+		return zap.String("request.id", "xyz")
+	})
+
+	handler := func(srv interface{}, stream grpclib.ServerStream) error {
+		return nil
+	}
+	if err := interceptor(nil, &fakeServerStream{ctx: context.Background()}, &grpclib.StreamServerInfo{FullMethod: "/svc/Stream"}, handler); err != nil {
+		t.Fatalf("interceptor: %v", err)
+	}
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry, got %d", len(entries))
+	}
+	if v, ok := entries[0].ContextMap()["request.id"]; !ok || v != "xyz" {
+		t.Fatalf("expected request.id=xyz field, got %v", entries[0].ContextMap())
+	}
+}
+
+func TestDefaultCodeToLevel(t *testing.T) {
+	cases := []struct {
+		code codes.Code
+		want zapcore.Level
+	}{
+		{codes.OK, zapcore.InfoLevel},
+		{codes.Canceled, zapcore.InfoLevel},
+		{codes.InvalidArgument, zapcore.InfoLevel},
+		{codes.NotFound, zapcore.InfoLevel},
+		{codes.AlreadyExists, zapcore.InfoLevel},
+		{codes.Unauthenticated, zapcore.InfoLevel},
+		{codes.DeadlineExceeded, zapcore.WarnLevel},
+		{codes.PermissionDenied, zapcore.WarnLevel},
+		{codes.ResourceExhausted, zapcore.WarnLevel},
+		{codes.FailedPrecondition, zapcore.WarnLevel},
+		{codes.Aborted, zapcore.WarnLevel},
+		{codes.OutOfRange, zapcore.WarnLevel},
+		{codes.Unavailable, zapcore.WarnLevel},
+		{codes.Unknown, zapcore.ErrorLevel},
+		{codes.Internal, zapcore.ErrorLevel},
+		{codes.DataLoss, zapcore.ErrorLevel},
+		{codes.Unimplemented, zapcore.ErrorLevel},
+	}
+
+	for _, tc := range cases {
+		if got := DefaultCodeToLevel(tc.code); got != tc.want {
+			t.Errorf("DefaultCodeToLevel(%s) = %s, want %s", tc.code, got, tc.want)
+		}
+	}
+}
+
+// TestNewUnaryServerInterceptorOptions proves NewUnaryServerInterceptor
+// honors WithCodeToLevel and WithDurationField. WithPayloadLogging is
+// also set, but the request is nil, so no payload entry is logged and
+// none is checked here.
+func TestNewUnaryServerInterceptorOptions(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core)
+
+	interceptor := NewUnaryServerInterceptor(logger,
+		WithCodeToLevel(func(code codes.Code) zapcore.Level {
+			return zapcore.DebugLevel
+		}),
+		WithDurationField(func(duration time.Duration) zap.Field {
+			return zap.String("grpc.duration", "fixed")
+		}),
+		WithPayloadLogging(func(ctx context.Context, fullMethod string) bool {
+			return true
+		}),
+	)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	if _, err := interceptor(context.Background(), nil, &grpclib.UnaryServerInfo{FullMethod: "/svc/Method"}, handler); err == nil {
+		t.Fatalf("expected handler error to be returned")
+	}
+
+	var callEntry *observer.LoggedEntry
+	for i, e := range logs.All() {
+		if e.Message == "finished unary call" {
+			callEntry = &logs.All()[i]
+		}
+	}
+	if callEntry == nil {
+		t.Fatalf("expected a \"finished unary call\" log entry")
+	}
+	if callEntry.Level != zapcore.DebugLevel {
+		t.Errorf("expected overridden level %s, got %s", zapcore.DebugLevel, callEntry.Level)
+	}
+	if v, ok := callEntry.ContextMap()["grpc.duration"]; !ok || v != "fixed" {
+		t.Errorf("expected overridden grpc.duration=fixed, got %v", callEntry.ContextMap())
+	}
+}
+
+type fakeServerStream struct {
+	grpclib.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}